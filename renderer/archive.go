@@ -0,0 +1,155 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/go-spook/spook/model"
+)
+
+// RenderArchive renders the list of posts published in the given year, or
+// in the given year and month when month is greater than zero.
+func (rd Renderer) RenderArchive(year, month, pageNumber int, dst io.Writer) (int, error) {
+	err := rd.validateConfig()
+	if err != nil {
+		return -1, err
+	}
+
+	posts := []model.Post{}
+	for _, post := range rd.Posts {
+		createdAt, err := time.Parse(time.RFC3339, post.CreatedAt)
+		if err != nil {
+			return -1, fmt.Errorf("Failed to parse created time of post %s: %v", post.Title, err)
+		}
+
+		if createdAt.Year() != year {
+			continue
+		}
+		if month > 0 && int(createdAt.Month()) != month {
+			continue
+		}
+
+		posts = append(posts, post)
+	}
+
+	// Set minimum page number
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+
+	// Make sure page number <= max page
+	maxPagination := rd.getMaxPagination(posts)
+	if pageNumber > maxPagination {
+		return -1, nil
+	}
+
+	archivePath := path.Join("/archive", strconv.Itoa(year))
+	title := strconv.Itoa(year)
+	if month > 0 {
+		archivePath = path.Join(archivePath, fmt.Sprintf("%02d", month))
+		title = fmt.Sprintf("%s %d", time.Month(month), year)
+	}
+
+	// Prepare layout
+	baseLayout := Layout{
+		WebsiteTitle: rd.Config.Title,
+		WebsiteOwner: rd.Config.Owner,
+		ContentTitle: title,
+		ContentDesc:  rd.Config.Description,
+		Pages:        rd.Pages,
+	}
+
+	list := List{
+		Layout:      baseLayout,
+		Type:        ARCHIVE,
+		Path:        archivePath,
+		Year:        year,
+		Month:       month,
+		Archives:    rd.Archives,
+		Authors:     rd.Authors,
+		CurrentPage: pageNumber,
+		MaxPage:     maxPagination,
+		Posts:       rd.getListPosts(posts, pageNumber),
+	}
+
+	// Resolve and execute templates
+	tpl, activeTemplate, err := rd.getResolver().Resolve(kindList, "")
+	if err != nil {
+		return -1, err
+	}
+
+	err = rd.executeTemplate(tpl, dst, activeTemplate, &list)
+	if err != nil {
+		return -1, err
+	}
+
+	return len(posts), nil
+}
+
+// RenderAuthorList renders the list of posts written by the given author.
+func (rd Renderer) RenderAuthorList(author string, pageNumber int, dst io.Writer) (int, error) {
+	err := rd.validateConfig()
+	if err != nil {
+		return -1, err
+	}
+
+	posts := []model.Post{}
+	for _, post := range rd.Posts {
+		postAuthor := post.Author
+		if postAuthor == "" {
+			postAuthor = rd.Config.Owner
+		}
+
+		if postAuthor == author {
+			posts = append(posts, post)
+		}
+	}
+
+	// Set minimum page number
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+
+	// Make sure page number <= max page
+	maxPagination := rd.getMaxPagination(posts)
+	if pageNumber > maxPagination {
+		return -1, nil
+	}
+
+	// Prepare layout
+	baseLayout := Layout{
+		WebsiteTitle: rd.Config.Title,
+		WebsiteOwner: rd.Config.Owner,
+		ContentTitle: author,
+		ContentDesc:  rd.Config.Description,
+		Pages:        rd.Pages,
+	}
+
+	list := List{
+		Layout:      baseLayout,
+		Type:        AUTHOR,
+		Path:        path.Join("/author", slugify(author)),
+		Author:      author,
+		Archives:    rd.Archives,
+		Authors:     rd.Authors,
+		CurrentPage: pageNumber,
+		MaxPage:     maxPagination,
+		Posts:       rd.getListPosts(posts, pageNumber),
+	}
+
+	// Resolve and execute templates
+	tpl, activeTemplate, err := rd.getResolver().Resolve(kindList, "")
+	if err != nil {
+		return -1, err
+	}
+
+	err = rd.executeTemplate(tpl, dst, activeTemplate, &list)
+	if err != nil {
+		return -1, err
+	}
+
+	return len(posts), nil
+}
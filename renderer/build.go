@@ -0,0 +1,316 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	fp "path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/go-spook/spook/model"
+	"golang.org/x/sync/errgroup"
+)
+
+// BuildOptions configures a full site Build.
+type BuildOptions struct {
+	// OutputDir is the directory the rendered site is written to.
+	OutputDir string
+	// Concurrency is the number of pages rendered at the same time.
+	// When zero, runtime.NumCPU() is used.
+	Concurrency int
+}
+
+// Build renders the whole site - front page, pages, posts, the
+// category/tag/archive/author list trees, feeds, the search index and
+// the sitemap/robots.txt - fanning out one job per post/page/list to a
+// worker pool, and aborts on the first error encountered.
+func (rd Renderer) Build(ctx context.Context, opts BuildOptions) error {
+	err := rd.validateConfig()
+	if err != nil {
+		return err
+	}
+
+	rd.resolver = newTemplateResolver(rd)
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, concurrency)
+
+	submit := func(job func() error) {
+		group.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return job()
+		})
+	}
+
+	submit(func() error {
+		return rd.buildFile(fp.Join(opts.OutputDir, "index.html"), rd.RenderFrontPage)
+	})
+
+	for _, page := range rd.Pages {
+		page := page
+		dst := fp.Join(opts.OutputDir, rd.pagePath(page), "index.html")
+
+		submit(func() error {
+			return rd.buildFile(dst, func(w io.Writer) error {
+				return rd.RenderPage(page, w)
+			})
+		})
+	}
+
+	for i, post := range rd.Posts {
+		post := post
+
+		var older, newer model.Post
+		if i > 0 {
+			older = rd.Posts[i-1]
+		}
+		if i < len(rd.Posts)-1 {
+			newer = rd.Posts[i+1]
+		}
+
+		dst := fp.Join(opts.OutputDir, rd.postPath(post), "index.html")
+
+		submit(func() error {
+			return rd.buildFile(dst, func(w io.Writer) error {
+				return rd.RenderPost(post, older, newer, w)
+			})
+		})
+	}
+
+	rd.buildGroupList(submit, CATEGORY, "/category", rd.Categories, opts)
+	rd.buildGroupList(submit, TAG, "/tag", rd.Tags, opts)
+	rd.buildArchives(submit, opts)
+	rd.buildAuthors(submit, opts)
+
+	if rd.Config.BaseURL != "" {
+		submit(func() error {
+			return rd.buildFile(fp.Join(opts.OutputDir, "feed.xml"), func(w io.Writer) error {
+				return rd.RenderFeed(FeedRSS, DEFAULT, "", w)
+			})
+		})
+
+		rd.buildGroupFeeds(submit, CATEGORY, "/category", rd.Categories, opts)
+		rd.buildGroupFeeds(submit, TAG, "/tag", rd.Tags, opts)
+		rd.buildSitemap(submit, opts)
+
+		submit(func() error {
+			return rd.buildFile(fp.Join(opts.OutputDir, "robots.txt"), rd.RenderRobots)
+		})
+	}
+
+	if rd.Config.Search.Enabled {
+		submit(func() error {
+			return rd.buildFile(fp.Join(opts.OutputDir, "search-index.json"), rd.RenderSearchIndex)
+		})
+	}
+
+	return group.Wait()
+}
+
+// buildGroupList submits one job per category/tag group that renders and
+// writes every paginated page of that group's list, so groups render
+// concurrently instead of one at a time on a single worker.
+func (rd Renderer) buildGroupList(submit func(func() error), listType ListType, urlPrefix string, groups []model.Group, opts BuildOptions) {
+	for _, group := range groups {
+		group := group
+
+		submit(func() error {
+			for page := 1; ; page++ {
+				var buff bytes.Buffer
+				count, err := rd.RenderList(listType, group.Name, page, &buff)
+				if err != nil {
+					return err
+				}
+				if count < 0 {
+					break
+				}
+
+				dst := fp.Join(opts.OutputDir, urlPrefix, group.Name)
+				if page > 1 {
+					dst = fp.Join(dst, "page", strconv.Itoa(page))
+				}
+
+				if err := writeFile(fp.Join(dst, "index.html"), buff.Bytes()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// buildArchives submits one job per archive year that renders and writes
+// every paginated page of that year's archive, plus its twelve month
+// archives, so years render concurrently instead of one at a time on a
+// single worker.
+func (rd Renderer) buildArchives(submit func(func() error), opts BuildOptions) {
+	for _, archive := range rd.Archives {
+		archive := archive
+
+		submit(func() error {
+			if err := rd.buildArchivePeriod(archive.Year, 0, opts); err != nil {
+				return err
+			}
+
+			for month := 1; month <= 12; month++ {
+				if err := rd.buildArchivePeriod(archive.Year, month, opts); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// buildArchivePeriod writes every paginated page of a single year, or
+// year/month, archive. Months with no posts render nothing, since
+// RenderArchive reports a negative count once pageNumber exceeds the
+// (possibly zero) max page.
+func (rd Renderer) buildArchivePeriod(year, month int, opts BuildOptions) error {
+	dst := fp.Join(opts.OutputDir, "archive", strconv.Itoa(year))
+	if month > 0 {
+		dst = fp.Join(dst, fmt.Sprintf("%02d", month))
+	}
+
+	for page := 1; ; page++ {
+		var buff bytes.Buffer
+		count, err := rd.RenderArchive(year, month, page, &buff)
+		if err != nil {
+			return err
+		}
+		if count < 0 {
+			break
+		}
+
+		pageDst := dst
+		if page > 1 {
+			pageDst = fp.Join(pageDst, "page", strconv.Itoa(page))
+		}
+
+		if err := writeFile(fp.Join(pageDst, "index.html"), buff.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildAuthors submits one job per author that renders and writes every
+// paginated page of that author's post list, under /author/<slug>, so
+// authors render concurrently instead of one at a time on a single
+// worker.
+func (rd Renderer) buildAuthors(submit func(func() error), opts BuildOptions) {
+	for _, author := range rd.Authors {
+		author := author
+
+		submit(func() error {
+			dst := fp.Join(opts.OutputDir, "author", slugify(author.Name))
+
+			for page := 1; ; page++ {
+				var buff bytes.Buffer
+				count, err := rd.RenderAuthorList(author.Name, page, &buff)
+				if err != nil {
+					return err
+				}
+				if count < 0 {
+					break
+				}
+
+				pageDst := dst
+				if page > 1 {
+					pageDst = fp.Join(pageDst, "page", strconv.Itoa(page))
+				}
+
+				if err := writeFile(fp.Join(pageDst, "index.html"), buff.Bytes()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// buildGroupFeeds submits one job per category/tag group that renders
+// and writes that group's RSS feed.xml, so groups render concurrently
+// instead of one at a time on a single worker.
+func (rd Renderer) buildGroupFeeds(submit func(func() error), listType ListType, urlPrefix string, groups []model.Group, opts BuildOptions) {
+	for _, group := range groups {
+		group := group
+		dst := fp.Join(opts.OutputDir, urlPrefix, group.Name, "feed.xml")
+
+		submit(func() error {
+			return rd.buildFile(dst, func(w io.Writer) error {
+				return rd.RenderFeed(FeedRSS, listType, group.Name, w)
+			})
+		})
+	}
+}
+
+// buildSitemap submits a job for sitemap.xml and one job per numbered
+// sitemap-N.xml page once the site has more than sitemapPageSize URLs,
+// so the split pages render concurrently instead of one at a time on a
+// single worker.
+func (rd Renderer) buildSitemap(submit func(func() error), opts BuildOptions) {
+	submit(func() error {
+		return rd.buildFile(fp.Join(opts.OutputDir, "sitemap.xml"), rd.RenderSitemap)
+	})
+
+	urls := rd.sitemapURLs()
+	pages := (len(urls) + sitemapPageSize - 1) / sitemapPageSize
+	for page := 1; page <= pages && len(urls) > sitemapPageSize; page++ {
+		page := page
+		dst := fp.Join(opts.OutputDir, fmt.Sprintf("sitemap-%d.xml", page))
+
+		submit(func() error {
+			return rd.buildFile(dst, func(w io.Writer) error {
+				return rd.RenderSitemapPage(page, w)
+			})
+		})
+	}
+}
+
+// buildFile creates dst (and its parent directories) and runs render
+// against it.
+func (rd Renderer) buildFile(dst string, render func(io.Writer) error) error {
+	if err := os.MkdirAll(fp.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return render(f)
+}
+
+// writeFile creates dst (and its parent directories) and writes data to it.
+func writeFile(dst string, data []byte) error {
+	if err := os.MkdirAll(fp.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,154 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/go-spook/spook/model"
+	"github.com/gorilla/feeds"
+)
+
+// FeedFormat is the output format used when rendering a feed.
+type FeedFormat int
+
+const (
+	// FeedRSS renders the feed as RSS 2.0.
+	FeedRSS FeedFormat = iota
+	// FeedAtom renders the feed as Atom 1.0.
+	FeedAtom
+	// FeedJSON renders the feed as JSON Feed 1.1.
+	FeedJSON
+
+	// defaultFeedLimit is used when Config.FeedLimit is not set.
+	defaultFeedLimit = 20
+)
+
+// RenderFeed renders a feed in the requested format for the front page,
+// or for a single category/tag when listType and groupName are set.
+func (rd Renderer) RenderFeed(format FeedFormat, listType ListType, groupName string, dst io.Writer) error {
+	err := rd.validateConfig()
+	if err != nil {
+		return err
+	}
+
+	if rd.Config.BaseURL == "" {
+		return fmt.Errorf("BaseURL is not configured, can't generate feed")
+	}
+
+	posts, feedPath, title := rd.filterFeedPosts(listType, groupName)
+
+	limit := rd.Config.FeedLimit
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+
+	if limit < len(posts) {
+		posts = posts[:limit]
+	}
+
+	feed := &feeds.Feed{
+		Title:       title,
+		Link:        &feeds.Link{Href: rd.absoluteURL(feedPath)},
+		Description: rd.Config.Description,
+		Author:      &feeds.Author{Name: rd.Config.Owner},
+		Created:     time.Now(),
+	}
+
+	for _, post := range posts {
+		item, err := rd.newFeedItem(post)
+		if err != nil {
+			return err
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	switch format {
+	case FeedAtom:
+		return feed.WriteAtom(dst)
+	case FeedJSON:
+		return feed.WriteJSON(dst)
+	default:
+		return feed.WriteRss(dst)
+	}
+}
+
+// newFeedItem converts a post into a feed item, reusing the same markdown
+// and syntax-highlighting pipeline as RenderPost.
+func (rd Renderer) newFeedItem(post model.Post) (*feeds.Item, error) {
+	content, err := readIndexFile(post.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	content = removeMetadata(content)
+	html, _, err := rd.renderMarkdown(content, PageMeta{Title: post.Title})
+	if err != nil {
+		return nil, err
+	}
+
+	author := post.Author
+	if author == "" {
+		author = rd.Config.Owner
+	}
+
+	created, err := time.Parse(time.RFC3339, post.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse created time of post %s: %v", post.Title, err)
+	}
+
+	updated := created
+	if post.UpdatedAt != "" {
+		updated, err = time.Parse(time.RFC3339, post.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse updated time of post %s: %v", post.Title, err)
+		}
+	}
+
+	return &feeds.Item{
+		Id:          rd.absoluteURL(rd.postPath(post)),
+		Title:       post.Title,
+		Link:        &feeds.Link{Href: rd.absoluteURL(rd.postPath(post))},
+		Author:      &feeds.Author{Name: author},
+		Description: post.Excerpt,
+		Content:     string(html),
+		Created:     created,
+		Updated:     updated,
+	}, nil
+}
+
+// filterFeedPosts returns the posts, feed path and title for the requested
+// feed, mirroring the filtering rules used by RenderList.
+func (rd Renderer) filterFeedPosts(listType ListType, groupName string) ([]model.Post, string, string) {
+	switch listType {
+	case CATEGORY:
+		filterName := groupName
+		if filterName == "uncategorized" {
+			filterName = ""
+		}
+
+		posts := []model.Post{}
+		for _, post := range rd.Posts {
+			if post.Category == filterName {
+				posts = append(posts, post)
+			}
+		}
+		return posts, path.Join("/category", groupName, "feed.xml"), rd.Config.Title + " - " + groupName
+
+	case TAG:
+		posts := []model.Post{}
+		for _, post := range rd.Posts {
+			for _, tag := range post.Tags {
+				if tag == groupName {
+					posts = append(posts, post)
+					break
+				}
+			}
+		}
+		return posts, path.Join("/tag", groupName, "feed.xml"), rd.Config.Title + " - " + groupName
+
+	default:
+		return rd.Posts, "/feed.xml", rd.Config.Title
+	}
+}
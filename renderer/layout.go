@@ -25,6 +25,11 @@ type List struct {
 	Posts       []model.Post
 	Tags        []model.Group
 	Categories  []model.Group
+	Archives    []model.ArchiveGroup
+	Authors     []model.Group
+	Year        int
+	Month       int
+	Author      string
 	CurrentPage int
 	MaxPage     int
 }
@@ -34,6 +39,7 @@ type Page struct {
 	Layout
 	Thumbnail string
 	HTML      template.HTML
+	TOC       template.HTML
 }
 
 // Post is layout that used in post
@@ -45,6 +51,7 @@ type Post struct {
 	Tags      []model.Group
 	Thumbnail string
 	HTML      template.HTML
+	TOC       template.HTML
 	Older     model.Post
 	Newer     model.Post
 }
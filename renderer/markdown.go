@@ -0,0 +1,89 @@
+package renderer
+
+import (
+	"html/template"
+)
+
+// PageMeta carries page-level metadata a MarkdownEngine may use while
+// rendering, such as building anchor links or a table of contents.
+type PageMeta struct {
+	Title string
+}
+
+// MarkdownEngine converts markdown source into HTML.
+type MarkdownEngine interface {
+	Render(src []byte, meta PageMeta) ([]byte, error)
+}
+
+// TOCGenerator is implemented by MarkdownEngine implementations that can
+// also produce a table of contents for the same source, such as Goldmark.
+type TOCGenerator interface {
+	TOC(src []byte) (template.HTML, error)
+}
+
+// Highlighter adds syntax highlighting to the <pre><code> blocks of
+// already-rendered HTML.
+type Highlighter interface {
+	Highlight(html []byte) ([]byte, error)
+}
+
+// markdownEngine returns rd.Markdown when set, otherwise builds one from
+// Config.Markdown.Engine ("goldmark" or "" for Blackfriday, spook's
+// original hard-coded behavior).
+func (rd Renderer) markdownEngine() MarkdownEngine {
+	if rd.Markdown != nil {
+		return rd.Markdown
+	}
+
+	switch rd.Config.Markdown.Engine {
+	case "goldmark":
+		return NewGoldmarkEngine()
+	default:
+		return BlackfridayEngine{}
+	}
+}
+
+// highlighter returns rd.Highlighter when set, otherwise builds one from
+// Config.Markdown.Highlight: empty keeps the original hard-coded
+// highlightCode pipeline, anything else is used as a Chroma style name.
+func (rd Renderer) highlighter() Highlighter {
+	if rd.Highlighter != nil {
+		return rd.Highlighter
+	}
+
+	if rd.Config.Markdown.Highlight == "" {
+		return blackfridayHighlighter{}
+	}
+
+	return ChromaHighlighter{
+		Style:       rd.Config.Markdown.Highlight,
+		LineNumbers: rd.Config.Markdown.LineNumbers,
+	}
+}
+
+// renderMarkdown runs src through the configured markdown engine and
+// highlighter, returning the resulting HTML and, if the engine supports
+// it, a table of contents.
+func (rd Renderer) renderMarkdown(src []byte, meta PageMeta) (template.HTML, template.HTML, error) {
+	engine := rd.markdownEngine()
+
+	html, err := engine.Render(src, meta)
+	if err != nil {
+		return "", "", err
+	}
+
+	html, err = rd.highlighter().Highlight(html)
+	if err != nil {
+		return "", "", err
+	}
+
+	toc := template.HTML("")
+	if gen, ok := engine.(TOCGenerator); ok {
+		toc, err = gen.TOC(src)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return template.HTML(html), toc, nil
+}
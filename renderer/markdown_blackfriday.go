@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	bf "gopkg.in/russross/blackfriday.v2"
+)
+
+// BlackfridayEngine renders markdown using Blackfriday v2. It is the
+// default MarkdownEngine, matching spook's original behavior, and does
+// not implement TOCGenerator.
+type BlackfridayEngine struct{}
+
+// Render implements MarkdownEngine.
+func (BlackfridayEngine) Render(src []byte, meta PageMeta) ([]byte, error) {
+	return bf.Run(src, bf.WithExtensions(mdExtensions)), nil
+}
+
+// blackfridayHighlighter is the default Highlighter, wrapping spook's
+// original hard-coded highlightCode pipeline.
+type blackfridayHighlighter struct{}
+
+// Highlight implements Highlighter.
+func (blackfridayHighlighter) Highlight(html []byte) ([]byte, error) {
+	return highlightCode(html), nil
+}
@@ -0,0 +1,72 @@
+package renderer
+
+import (
+	"bytes"
+	gohtml "html"
+	"regexp"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// codeBlockPattern matches a fenced code block as rendered by a CommonMark
+// engine, e.g. <pre><code class="language-go">...</code></pre>.
+var codeBlockPattern = regexp.MustCompile(`(?s)<pre><code class="language-([\w-]+)">(.*?)</code></pre>`)
+
+// ChromaHighlighter highlights fenced code blocks using Chroma, with a
+// configurable style (see Config.Markdown.Highlight) and optional line
+// numbers.
+type ChromaHighlighter struct {
+	Style       string
+	LineNumbers bool
+}
+
+// Highlight implements Highlighter.
+func (h ChromaHighlighter) Highlight(src []byte) ([]byte, error) {
+	style := styles.Get(h.Style)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	formatter := chromahtml.New(
+		chromahtml.WithClasses(false),
+		chromahtml.WithLineNumbers(h.LineNumbers),
+	)
+
+	var formatErr error
+	result := codeBlockPattern.ReplaceAllFunc(src, func(match []byte) []byte {
+		if formatErr != nil {
+			return match
+		}
+
+		groups := codeBlockPattern.FindSubmatch(match)
+		lang := string(groups[1])
+		code := gohtml.UnescapeString(string(groups[2]))
+
+		lexer := lexers.Get(lang)
+		if lexer == nil {
+			lexer = lexers.Fallback
+		}
+
+		iterator, err := lexer.Tokenise(nil, code)
+		if err != nil {
+			formatErr = err
+			return match
+		}
+
+		var buff bytes.Buffer
+		if err := formatter.Format(&buff, style, iterator); err != nil {
+			formatErr = err
+			return match
+		}
+
+		return buff.Bytes()
+	})
+
+	if formatErr != nil {
+		return nil, formatErr
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package renderer
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"go.abhg.dev/goldmark/anchor"
+	"go.abhg.dev/goldmark/toc"
+)
+
+// GoldmarkEngine renders markdown using Goldmark, with CommonMark, GitHub
+// Flavored Markdown (tables, task lists, strikethrough, autolinks),
+// footnotes, smart typography and heading anchor links enabled. It also
+// implements TOCGenerator.
+type GoldmarkEngine struct {
+	md goldmark.Markdown
+}
+
+// NewGoldmarkEngine builds a ready-to-use GoldmarkEngine.
+func NewGoldmarkEngine() *GoldmarkEngine {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+			extension.Typographer,
+			&anchor.Extender{},
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	return &GoldmarkEngine{md: md}
+}
+
+// Render implements MarkdownEngine.
+func (e *GoldmarkEngine) Render(src []byte, meta PageMeta) ([]byte, error) {
+	var buff bytes.Buffer
+	if err := e.md.Convert(src, &buff); err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
+
+// TOC implements TOCGenerator, returning the heading outline of src
+// rendered as a nested list.
+func (e *GoldmarkEngine) TOC(src []byte) (template.HTML, error) {
+	doc := e.md.Parser().Parse(text.NewReader(src))
+
+	tree, err := toc.Inspect(doc, src)
+	if err != nil {
+		return "", err
+	}
+	if tree == nil || len(tree.Items) == 0 {
+		return "", nil
+	}
+
+	var buff bytes.Buffer
+	if err := e.md.Renderer().Render(&buff, src, toc.RenderList(tree)); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buff.String()), nil
+}
+
+var (
+	_ MarkdownEngine = (*GoldmarkEngine)(nil)
+	_ TOCGenerator   = (*GoldmarkEngine)(nil)
+)
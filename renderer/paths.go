@@ -0,0 +1,31 @@
+package renderer
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-spook/spook/model"
+)
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a title into a URL-friendly, lowercase, hyphenated slug.
+func slugify(title string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// postPath returns the public URL path of a post.
+func (rd Renderer) postPath(post model.Post) string {
+	return path.Join("/post", slugify(post.Title))
+}
+
+// pagePath returns the public URL path of a page.
+func (rd Renderer) pagePath(page model.Page) string {
+	return path.Join("/", slugify(page.Title))
+}
+
+// absoluteURL joins Config.BaseURL with a site-relative path.
+func (rd Renderer) absoluteURL(urlPath string) string {
+	return strings.TrimRight(rd.Config.BaseURL, "/") + urlPath
+}
@@ -5,12 +5,10 @@ import (
 	"fmt"
 	"html/template"
 	"io"
-	"io/ioutil"
 	"math"
 	"path"
 	fp "path/filepath"
 	"sort"
-	"strings"
 
 	"github.com/go-spook/spook/model"
 	"github.com/tdewolff/minify"
@@ -28,6 +26,12 @@ const (
 	CATEGORY
 	// TAG means the list is list that only shows posts with specified tags.
 	TAG
+	// ARCHIVE means the list is list that only shows posts published in a
+	// specified year, or year and month.
+	ARCHIVE
+	// AUTHOR means the list is list that only shows posts written by a
+	// specified author.
+	AUTHOR
 
 	mdExtensions = bf.CommonExtensions | bf.Footnotes | bf.AutoHeadingIDs | bf.HeadingIDs
 )
@@ -39,8 +43,22 @@ type Renderer struct {
 	Posts      []model.Post
 	Tags       []model.Group
 	Categories []model.Group
+	Archives   []model.ArchiveGroup
+	Authors    []model.Group
 	Minimize   bool
 	RootDir    string
+
+	// Markdown and Highlighter select the markdown rendering and syntax
+	// highlighting backends. When left nil, they are built from
+	// Config.Markdown, which selects Blackfriday and its matching
+	// highlighter unless an engine or a highlight style is set there.
+	Markdown    MarkdownEngine
+	Highlighter Highlighter
+
+	// resolver, when set by Build, caches parsed template sets for the
+	// whole run. Ad-hoc renders that don't go through Build leave this
+	// nil and resolve templates uncached.
+	resolver *templateResolver
 }
 
 var funcsMap = template.FuncMap{
@@ -59,25 +77,15 @@ func (rd Renderer) RenderFrontPage(dst io.Writer) error {
 		return err
 	}
 
-	// Prepare templates
-	themeDir := fp.Join(rd.RootDir, "theme", rd.Config.Theme)
-	tplList := fp.Join(themeDir, "list.html")
-	tplFrontPage := fp.Join(themeDir, "frontpage.html")
-
-	templates, err := rd.getBaseTemplates()
+	// Resolve templates, falling back to the list template when the theme
+	// has no dedicated front page template.
+	resolver := rd.getResolver()
+	tpl, activeTemplate, err := resolver.Resolve(kindFrontPage, "")
 	if err != nil {
-		return err
-	}
-
-	activeTemplate := ""
-	if fileExists(tplFrontPage) {
-		activeTemplate = "frontpage.html"
-		templates = append(templates, tplFrontPage)
-	} else if fileExists(tplList) {
-		activeTemplate = "list.html"
-		templates = append(templates, tplList)
-	} else {
-		return fmt.Errorf("Template for frontpage and list is not exist")
+		tpl, activeTemplate, err = resolver.Resolve(kindList, "")
+		if err != nil {
+			return fmt.Errorf("Template for frontpage and list is not exist")
+		}
 	}
 
 	// Prepare layout
@@ -98,12 +106,8 @@ func (rd Renderer) RenderFrontPage(dst io.Writer) error {
 		Posts:       rd.getListPosts(rd.Posts, 1),
 		Categories:  rd.Categories,
 		Tags:        rd.Tags,
-	}
-
-	// Execute templates
-	tpl, err := template.New("").Funcs(funcsMap).ParseFiles(templates...)
-	if err != nil {
-		return err
+		Archives:    rd.Archives,
+		Authors:     rd.Authors,
 	}
 
 	return rd.executeTemplate(tpl, dst, activeTemplate, &frontPage)
@@ -117,28 +121,19 @@ func (rd Renderer) RenderList(listType ListType, groupName string, pageNumber in
 		return -1, err
 	}
 
-	// Prepare templates
-	themeDir := fp.Join(rd.RootDir, "theme", rd.Config.Theme)
-	tplList := fp.Join(themeDir, "list.html")
-	if !fileExists(tplList) {
-		return -1, fmt.Errorf("Template for list is not exist")
-	}
-
-	templates, err := rd.getBaseTemplates()
-	if err != nil {
-		return -1, err
-	}
-
-	templates = append(templates, tplList)
+	// Filter posts by group. For the CATEGORY uncategorized bucket, the
+	// filter compares against "", but groupName itself is left untouched
+	// so ContentTitle, the list path and the template lookup below still
+	// see "uncategorized" - mirroring filterFeedPosts in feed.go.
+	filterName := groupName
 
-	// Filter posts by group
 	filterCategory := func(post model.Post) bool {
-		return post.Category == groupName
+		return post.Category == filterName
 	}
 
 	filterTag := func(post model.Post) bool {
 		for _, tag := range post.Tags {
-			if tag == groupName {
+			if tag == filterName {
 				return true
 			}
 		}
@@ -152,8 +147,8 @@ func (rd Renderer) RenderList(listType ListType, groupName string, pageNumber in
 		filter := filterTag
 		if listType == CATEGORY {
 			filter = filterCategory
-			if groupName == "uncategorized" {
-				groupName = ""
+			if filterName == "uncategorized" {
+				filterName = ""
 			}
 		}
 
@@ -200,13 +195,13 @@ func (rd Renderer) RenderList(listType ListType, groupName string, pageNumber in
 		Posts:       rd.getListPosts(posts, pageNumber),
 	}
 
-	// Execute templates
-	tpl, err := template.New("").Funcs(funcsMap).ParseFiles(templates...)
+	// Resolve and execute templates
+	tpl, activeTemplate, err := rd.getResolver().Resolve(kindList, groupName)
 	if err != nil {
 		return -1, err
 	}
 
-	err = rd.executeTemplate(tpl, dst, "list.html", &list)
+	err = rd.executeTemplate(tpl, dst, activeTemplate, &list)
 	if err != nil {
 		return -1, err
 	}
@@ -222,20 +217,6 @@ func (rd Renderer) RenderPage(page model.Page, dst io.Writer) error {
 		return err
 	}
 
-	// Prepare templates
-	themeDir := fp.Join(rd.RootDir, "theme", rd.Config.Theme)
-	tplPage := fp.Join(themeDir, "page.html")
-	if !fileExists(tplPage) {
-		return fmt.Errorf("Template for page is not exist")
-	}
-
-	templates, err := rd.getBaseTemplates()
-	if err != nil {
-		return err
-	}
-
-	templates = append(templates, tplPage)
-
 	// Open index file
 	content, err := readIndexFile(page.Path)
 	if err != nil {
@@ -243,8 +224,10 @@ func (rd Renderer) RenderPage(page model.Page, dst io.Writer) error {
 	}
 
 	content = removeMetadata(content)
-	html := bf.Run(content, bf.WithExtensions(mdExtensions))
-	html = highlightCode(html)
+	html, toc, err := rd.renderMarkdown(content, PageMeta{Title: page.Title})
+	if err != nil {
+		return err
+	}
 
 	// Prepare layout
 	baseLayout := Layout{
@@ -258,16 +241,17 @@ func (rd Renderer) RenderPage(page model.Page, dst io.Writer) error {
 	pageLayout := Page{
 		Layout:    baseLayout,
 		Thumbnail: page.Thumbnail,
-		HTML:      template.HTML(html),
+		HTML:      html,
+		TOC:       toc,
 	}
 
-	// Execute templates
-	tpl, err := template.New("").Funcs(funcsMap).ParseFiles(templates...)
+	// Resolve and execute templates
+	tpl, activeTemplate, err := rd.getResolver().Resolve(kindPage, "")
 	if err != nil {
 		return err
 	}
 
-	return rd.executeTemplate(tpl, dst, "page.html", &pageLayout)
+	return rd.executeTemplate(tpl, dst, activeTemplate, &pageLayout)
 }
 
 // RenderPost renders post template.
@@ -278,20 +262,6 @@ func (rd Renderer) RenderPost(post, olderPost, newerPost model.Post, dst io.Writ
 		return err
 	}
 
-	// Prepare templates
-	themeDir := fp.Join(rd.RootDir, "theme", rd.Config.Theme)
-	tplPost := fp.Join(themeDir, "post.html")
-	if !fileExists(tplPost) {
-		return fmt.Errorf("Template for post is not exist")
-	}
-
-	templates, err := rd.getBaseTemplates()
-	if err != nil {
-		return err
-	}
-
-	templates = append(templates, tplPost)
-
 	// Convert category and tags of post into Group
 	category := model.Group{
 		Name: post.Category,
@@ -327,8 +297,10 @@ func (rd Renderer) RenderPost(post, olderPost, newerPost model.Post, dst io.Writ
 	}
 
 	content = removeMetadata(content)
-	html := bf.Run(content, bf.WithExtensions(mdExtensions))
-	html = highlightCode(html)
+	html, toc, err := rd.renderMarkdown(content, PageMeta{Title: post.Title})
+	if err != nil {
+		return err
+	}
 
 	// Prepare layout
 	baseLayout := Layout{
@@ -347,18 +319,19 @@ func (rd Renderer) RenderPost(post, olderPost, newerPost model.Post, dst io.Writ
 		Category:  category,
 		Tags:      tags,
 		Thumbnail: post.Thumbnail,
-		HTML:      template.HTML(html),
+		HTML:      html,
+		TOC:       toc,
 		Older:     olderPost,
 		Newer:     newerPost,
 	}
 
-	// Execute templates
-	tpl, err := template.New("").Funcs(funcsMap).ParseFiles(templates...)
+	// Resolve and execute templates
+	tpl, activeTemplate, err := rd.getResolver().Resolve(kindPost, post.Category)
 	if err != nil {
 		return err
 	}
 
-	return rd.executeTemplate(tpl, dst, "post.html", &postLayout)
+	return rd.executeTemplate(tpl, dst, activeTemplate, &postLayout)
 }
 
 // validateConfig verifies that the config file is valid.
@@ -370,30 +343,6 @@ func (rd Renderer) validateConfig() error {
 	return nil
 }
 
-// getBaseTemplates fetch list of base templates that used in the theme.
-// The base template is all HTML that prefixed with underscore character,
-// e.g _footer.html, _header.html, etc.
-func (rd Renderer) getBaseTemplates() ([]string, error) {
-	themeDir := fp.Join(rd.RootDir, "theme", rd.Config.Theme)
-	items, err := ioutil.ReadDir(themeDir)
-	if err != nil {
-		return []string{}, err
-	}
-
-	templates := []string{}
-	for _, item := range items {
-		if item.IsDir() {
-			continue
-		}
-
-		if strings.HasSuffix(item.Name(), ".html") && strings.HasPrefix(item.Name(), "_") {
-			templates = append(templates, fp.Join(themeDir, item.Name()))
-		}
-	}
-
-	return templates, nil
-}
-
 // getMaxPagination calculates the max page number following the configuration.
 func (rd Renderer) getMaxPagination(posts []model.Post) int {
 	nPosts := len(posts)
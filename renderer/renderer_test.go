@@ -0,0 +1,56 @@
+package renderer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	fp "path/filepath"
+	"testing"
+
+	"github.com/go-spook/spook/model"
+)
+
+// TestRenderListUsesSectionTemplateForUncategorized verifies that the
+// CATEGORY "uncategorized" bucket still resolves its own
+// theme/uncategorized/list.html override, instead of always falling back
+// to theme/_default/list.html because the section name used to look up
+// the template had already been blanked out for post filtering.
+func TestRenderListUsesSectionTemplateForUncategorized(t *testing.T) {
+	dir := t.TempDir()
+	themeDir := fp.Join(dir, "theme", "test")
+
+	if err := os.MkdirAll(fp.Join(themeDir, "uncategorized"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(fp.Join(themeDir, "_default"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(path, content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(fp.Join(themeDir, "uncategorized", "list.html"), "UNCATEGORIZED-MARKER")
+	write(fp.Join(themeDir, "_default", "list.html"), "DEFAULT-MARKER")
+
+	rd := Renderer{
+		Config:  model.Config{Theme: "test", Pagination: 10},
+		RootDir: dir,
+		Posts: []model.Post{
+			{Title: "A", Category: "", CreatedAt: "2024-01-01T00:00:00Z"},
+		},
+	}
+
+	var buff bytes.Buffer
+	count, err := rd.RenderList(CATEGORY, "uncategorized", 1, &buff)
+	if err != nil {
+		t.Fatalf("RenderList: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 post in the uncategorized bucket, got %d", count)
+	}
+	if buff.String() != "UNCATEGORIZED-MARKER" {
+		t.Fatalf("expected theme/uncategorized/list.html to be used, got %q", buff.String())
+	}
+}
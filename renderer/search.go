@@ -0,0 +1,211 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// SearchRecord is a single document in the client-side search index.
+type SearchRecord struct {
+	URL       string   `json:"url"`
+	Title     string   `json:"title"`
+	Excerpt   string   `json:"excerpt,omitempty"`
+	Category  string   `json:"category,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Author    string   `json:"author,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	Body      string   `json:"body,omitempty"`
+}
+
+// searchPosting is one entry of a token's postings list in a prebuilt
+// inverted index: the document it occurs in and how many times.
+type searchPosting struct {
+	DocID int `json:"doc_id"`
+	TF    int `json:"tf"`
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	searchTokenizer   = regexp.MustCompile(`[a-zA-Z0-9']+`)
+)
+
+// RenderSearchIndex emits a client-side search index covering every post
+// and page, gated behind Config.Search.Enabled. When Config.Search.Fields
+// is non-empty, only the named fields are included in each record. When
+// Config.Search.Prebuilt is true, a Lunr-compatible inverted index (token
+// to list of documents with term frequencies) is emitted alongside the
+// records, pruned of any Config.Search.Stopwords.
+func (rd Renderer) RenderSearchIndex(dst io.Writer) error {
+	if !rd.Config.Search.Enabled {
+		return fmt.Errorf("Search is not enabled in configuration file")
+	}
+
+	records, err := rd.buildSearchRecords()
+	if err != nil {
+		return err
+	}
+
+	if !rd.Config.Search.Prebuilt {
+		return json.NewEncoder(dst).Encode(struct {
+			Records []SearchRecord `json:"records"`
+		}{Records: filterSearchFields(records, rd.Config.Search.Fields)})
+	}
+
+	// The index must be built from the unfiltered records: Fields only
+	// trims what gets serialized, and tokenizing blanked-out titles/bodies
+	// would leave the prebuilt index with little to no postings.
+	index := buildInvertedIndex(records, rd.Config.Search.Stopwords)
+
+	return json.NewEncoder(dst).Encode(struct {
+		Records []SearchRecord             `json:"records"`
+		Index   map[string][]searchPosting `json:"index"`
+	}{
+		Records: filterSearchFields(records, rd.Config.Search.Fields),
+		Index:   index,
+	})
+}
+
+// buildSearchRecords collects a SearchRecord for every post and page,
+// using the same markdown pipeline as RenderPost/RenderPage to produce
+// the plain-text body.
+func (rd Renderer) buildSearchRecords() ([]SearchRecord, error) {
+	records := make([]SearchRecord, 0, len(rd.Posts)+len(rd.Pages))
+
+	for _, post := range rd.Posts {
+		body, err := rd.plainTextBody(post.Path, post.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		author := post.Author
+		if author == "" {
+			author = rd.Config.Owner
+		}
+
+		records = append(records, SearchRecord{
+			URL:       rd.postPath(post),
+			Title:     post.Title,
+			Excerpt:   post.Excerpt,
+			Category:  post.Category,
+			Tags:      post.Tags,
+			Author:    author,
+			CreatedAt: post.CreatedAt,
+			Body:      body,
+		})
+	}
+
+	for _, page := range rd.Pages {
+		body, err := rd.plainTextBody(page.Path, page.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, SearchRecord{
+			URL:     rd.pagePath(page),
+			Title:   page.Title,
+			Excerpt: page.Excerpt,
+			Body:    body,
+		})
+	}
+
+	return records, nil
+}
+
+// plainTextBody renders the markdown file at srcPath and strips it down
+// to plain, whitespace-collapsed text.
+func (rd Renderer) plainTextBody(srcPath, title string) (string, error) {
+	content, err := readIndexFile(srcPath)
+	if err != nil {
+		return "", err
+	}
+
+	content = removeMetadata(content)
+	html, _, err := rd.renderMarkdown(content, PageMeta{Title: title})
+	if err != nil {
+		return "", err
+	}
+
+	return stripHTML(string(html)), nil
+}
+
+// stripHTML removes tags and collapses whitespace.
+func stripHTML(html string) string {
+	text := htmlTagPattern.ReplaceAllString(html, " ")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// filterSearchFields zeroes out every SearchRecord field not named in
+// fields. An empty fields list keeps every field.
+func filterSearchFields(records []SearchRecord, fields []string) []SearchRecord {
+	if len(fields) == 0 {
+		return records
+	}
+
+	keep := map[string]bool{}
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	filtered := make([]SearchRecord, len(records))
+	for i, record := range records {
+		if !keep["url"] {
+			record.URL = ""
+		}
+		if !keep["title"] {
+			record.Title = ""
+		}
+		if !keep["excerpt"] {
+			record.Excerpt = ""
+		}
+		if !keep["category"] {
+			record.Category = ""
+		}
+		if !keep["tags"] {
+			record.Tags = nil
+		}
+		if !keep["author"] {
+			record.Author = ""
+		}
+		if !keep["created_at"] {
+			record.CreatedAt = ""
+		}
+		if !keep["body"] {
+			record.Body = ""
+		}
+		filtered[i] = record
+	}
+
+	return filtered
+}
+
+// buildInvertedIndex tokenizes the title and body of every record into a
+// Lunr-compatible token -> postings map, skipping any word in stopwords.
+func buildInvertedIndex(records []SearchRecord, stopwords []string) map[string][]searchPosting {
+	stop := map[string]bool{}
+	for _, word := range stopwords {
+		stop[strings.ToLower(word)] = true
+	}
+
+	index := map[string][]searchPosting{}
+	for docID, record := range records {
+		frequencies := map[string]int{}
+		text := strings.ToLower(record.Title + " " + record.Body)
+
+		for _, token := range searchTokenizer.FindAllString(text, -1) {
+			if stop[token] {
+				continue
+			}
+			frequencies[token]++
+		}
+
+		for token, tf := range frequencies {
+			index[token] = append(index[token], searchPosting{DocID: docID, TF: tf})
+		}
+	}
+
+	return index
+}
@@ -0,0 +1,57 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	fp "path/filepath"
+	"testing"
+
+	"github.com/go-spook/spook/model"
+)
+
+// TestRenderSearchIndexPrebuiltKeepsFilteredTokens verifies that the
+// prebuilt inverted index is built from the full post/page text even
+// when Config.Search.Fields trims Title and Body out of the serialized
+// records - the combination the prebuilt index exists for.
+func TestRenderSearchIndexPrebuiltKeepsFilteredTokens(t *testing.T) {
+	dir := t.TempDir()
+	postPath := fp.Join(dir, "post.md")
+	if err := ioutil.WriteFile(postPath, []byte("this post mentions foobarqux"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := Renderer{
+		Config: model.Config{
+			Search: model.SearchConfig{
+				Enabled:  true,
+				Fields:   []string{"url"},
+				Prebuilt: true,
+			},
+		},
+		Posts: []model.Post{
+			{Path: postPath, Title: "Hello World", CreatedAt: "2024-01-01T00:00:00Z"},
+		},
+	}
+
+	var buff bytes.Buffer
+	if err := rd.RenderSearchIndex(&buff); err != nil {
+		t.Fatalf("RenderSearchIndex: %v", err)
+	}
+
+	var out struct {
+		Records []SearchRecord             `json:"records"`
+		Index   map[string][]searchPosting `json:"index"`
+	}
+	if err := json.Unmarshal(buff.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(out.Records) != 1 || out.Records[0].Title != "" {
+		t.Fatalf("Fields=[url] should blank Title in the serialized record, got %+v", out.Records)
+	}
+
+	if _, ok := out.Index["foobarqux"]; !ok {
+		t.Fatalf("prebuilt index is missing postings for a word only present in the filtered-out body, index=%v", out.Index)
+	}
+}
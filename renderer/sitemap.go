@@ -0,0 +1,175 @@
+package renderer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+)
+
+// sitemapXMLNS is the namespace of the sitemap 0.9 schema.
+const sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapPageSize is the maximum number of URLs in a single sitemap file,
+// per the sitemap 0.9 schema.
+const sitemapPageSize = 50000
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	XMLNS    string              `xml:"xmlns,attr"`
+	Sitemaps []sitemapIndexEntry `xml:"sitemap"`
+}
+
+type sitemapIndexEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// RenderSitemap emits sitemap.xml, listing the front page, every page,
+// every post, every category/tag index and every yearly archive index;
+// month archive indexes are not listed. When the site has
+// more than 50,000 URLs, it instead emits a sitemap index referencing
+// /sitemap-N.xml pages, each of which must be rendered separately with
+// RenderSitemapPage.
+func (rd Renderer) RenderSitemap(dst io.Writer) error {
+	if rd.Config.BaseURL == "" {
+		return fmt.Errorf("BaseURL is not configured, can't generate sitemap")
+	}
+
+	urls := rd.sitemapURLs()
+	if len(urls) <= sitemapPageSize {
+		return writeSitemapPage(dst, urls)
+	}
+
+	pages := (len(urls) + sitemapPageSize - 1) / sitemapPageSize
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	for i := 0; i < pages; i++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapIndexEntry{
+			Loc: rd.absoluteURL(fmt.Sprintf("/sitemap-%d.xml", i+1)),
+		})
+	}
+
+	if _, err := io.WriteString(dst, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(dst)
+	encoder.Indent("", "  ")
+	return encoder.Encode(index)
+}
+
+// RenderSitemapPage renders the given 1-indexed page of a split sitemap.
+func (rd Renderer) RenderSitemapPage(page int, dst io.Writer) error {
+	if rd.Config.BaseURL == "" {
+		return fmt.Errorf("BaseURL is not configured, can't generate sitemap")
+	}
+
+	urls := rd.sitemapURLs()
+
+	start := (page - 1) * sitemapPageSize
+	if page < 1 || start >= len(urls) {
+		return fmt.Errorf("Sitemap page %d does not exist", page)
+	}
+
+	end := start + sitemapPageSize
+	if end > len(urls) {
+		end = len(urls)
+	}
+
+	return writeSitemapPage(dst, urls[start:end])
+}
+
+// RenderRobots emits robots.txt, honoring Config.Robots.Disallow and
+// pointing crawlers at the sitemap.
+func (rd Renderer) RenderRobots(dst io.Writer) error {
+	if rd.Config.BaseURL == "" {
+		return fmt.Errorf("BaseURL is not configured, can't generate robots.txt")
+	}
+
+	fmt.Fprintln(dst, "User-agent: *")
+	for _, disallow := range rd.Config.Robots.Disallow {
+		fmt.Fprintf(dst, "Disallow: %s\n", disallow)
+	}
+
+	fmt.Fprintln(dst)
+	fmt.Fprintf(dst, "Sitemap: %s\n", rd.absoluteURL("/sitemap.xml"))
+
+	return nil
+}
+
+// writeSitemapPage writes a single urlset document to dst.
+func writeSitemapPage(dst io.Writer, urls []sitemapURL) error {
+	if _, err := io.WriteString(dst, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(dst)
+	encoder.Indent("", "  ")
+	return encoder.Encode(sitemapURLSet{XMLNS: sitemapXMLNS, URLs: urls})
+}
+
+// sitemapURLs builds the full, unsplit list of sitemap entries.
+func (rd Renderer) sitemapURLs() []sitemapURL {
+	urls := []sitemapURL{
+		rd.newSitemapURL("/", "", "frontpage"),
+	}
+
+	for _, page := range rd.Pages {
+		lastmod := page.UpdatedAt
+		if lastmod == "" {
+			lastmod = page.CreatedAt
+		}
+		urls = append(urls, rd.newSitemapURL(rd.pagePath(page), lastmod, "page"))
+	}
+
+	for _, post := range rd.Posts {
+		lastmod := post.UpdatedAt
+		if lastmod == "" {
+			lastmod = post.CreatedAt
+		}
+		urls = append(urls, rd.newSitemapURL(rd.postPath(post), lastmod, "post"))
+	}
+
+	for _, category := range rd.Categories {
+		urls = append(urls, rd.newSitemapURL(path.Join("/category", category.Name), "", "category"))
+	}
+
+	for _, tag := range rd.Tags {
+		urls = append(urls, rd.newSitemapURL(path.Join("/tag", tag.Name), "", "tag"))
+	}
+
+	for _, archive := range rd.Archives {
+		urls = append(urls, rd.newSitemapURL(path.Join("/archive", strconv.Itoa(archive.Year)), "", "archive"))
+	}
+
+	return urls
+}
+
+// newSitemapURL builds a sitemapURL for kind, looking up its changefreq
+// and priority from Config.Sitemap.
+func (rd Renderer) newSitemapURL(urlPath, lastmod, kind string) sitemapURL {
+	priority := ""
+	if p, ok := rd.Config.Sitemap.Priority[kind]; ok {
+		priority = strconv.FormatFloat(p, 'f', -1, 64)
+	}
+
+	return sitemapURL{
+		Loc:        rd.absoluteURL(urlPath),
+		LastMod:    lastmod,
+		ChangeFreq: rd.Config.Sitemap.ChangeFreq[kind],
+		Priority:   priority,
+	}
+}
@@ -0,0 +1,185 @@
+package renderer
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	fp "path/filepath"
+	"strings"
+	"sync"
+)
+
+// pageKind identifies the kind of page being rendered, used as part of the
+// cache key and the template lookup order in templateResolver.
+type pageKind string
+
+const (
+	kindFrontPage pageKind = "frontpage"
+	kindList      pageKind = "list"
+	kindPage      pageKind = "page"
+	kindPost      pageKind = "post"
+)
+
+// templateResolver implements Hugo-style template lookup for a single
+// theme. For a given page kind and an optional section (e.g. a category
+// or tag name) it finds the most specific leaf template and, if any,
+// the most specific base template, then composes them together with the
+// theme's partials into one parsed template set.
+//
+// Template sets are parsed at most once per (kind, section) pair; repeat
+// renders during the same build reuse the cached set. A templateResolver
+// is scoped to a single Build (or ad-hoc render) rather than kept alive
+// for the life of the process, so theme changes on disk are picked up by
+// the next Build instead of being masked by a stale cache.
+type templateResolver struct {
+	themeDir string
+
+	mu    sync.RWMutex
+	cache map[string]*resolvedTemplate
+}
+
+// newTemplateResolver creates an empty templateResolver for the given
+// renderer's theme.
+func newTemplateResolver(rd Renderer) *templateResolver {
+	return &templateResolver{
+		themeDir: fp.Join(rd.RootDir, "theme", rd.Config.Theme),
+		cache:    map[string]*resolvedTemplate{},
+	}
+}
+
+// getResolver returns the templateResolver this renderer should use:
+// rd.resolver when Build has set one up for the whole run, or a
+// freshly-created, uncached one for a one-off render.
+func (rd Renderer) getResolver() *templateResolver {
+	if rd.resolver != nil {
+		return rd.resolver
+	}
+
+	return newTemplateResolver(rd)
+}
+
+// Resolve returns the parsed template set and the name of the template to
+// execute for the given kind and section. section may be empty, in which
+// case only the theme-wide and _default templates are considered.
+func (tr *templateResolver) Resolve(kind pageKind, section string) (*template.Template, string, error) {
+	key := string(kind) + "|" + section
+
+	tr.mu.RLock()
+	set, ok := tr.cache[key]
+	tr.mu.RUnlock()
+	if ok {
+		return set.Template, set.activeTemplate, nil
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	// Another render might have populated the cache while we were
+	// waiting for the write lock.
+	if set, ok := tr.cache[key]; ok {
+		return set.Template, set.activeTemplate, nil
+	}
+
+	leaf := tr.findLeaf(kind, section)
+	if leaf == "" {
+		return nil, "", fmt.Errorf("Template for %s is not exist", kind)
+	}
+
+	active := fp.Base(leaf)
+	files := []string{leaf}
+	if base := tr.findBase(kind, section); base != "" {
+		active = fp.Base(base)
+		files = append(files, base)
+	}
+
+	partials, err := tr.findPartials()
+	if err != nil {
+		return nil, "", err
+	}
+	files = append(files, partials...)
+
+	tpl, err := template.New("").Funcs(funcsMap).ParseFiles(files...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tr.cache[key] = &resolvedTemplate{Template: tpl, activeTemplate: active}
+
+	return tpl, active, nil
+}
+
+// resolvedTemplate pairs a parsed template set with the name of the
+// template within it that should be executed.
+type resolvedTemplate struct {
+	*template.Template
+	activeTemplate string
+}
+
+// findLeaf looks up the most specific leaf template for kind/section, in
+// order: theme/<section>/<kind>.html, theme/<kind>.html,
+// theme/_default/<kind>.html.
+func (tr *templateResolver) findLeaf(kind pageKind, section string) string {
+	candidates := []string{}
+	if section != "" {
+		candidates = append(candidates, fp.Join(tr.themeDir, section, string(kind)+".html"))
+	}
+	candidates = append(candidates,
+		fp.Join(tr.themeDir, string(kind)+".html"),
+		fp.Join(tr.themeDir, "_default", string(kind)+".html"),
+	)
+
+	return firstExisting(candidates)
+}
+
+// findBase looks up the most specific base template for kind/section, in
+// order: <section>/<kind>-baseof.html, <section>/baseof.html,
+// _default/<kind>-baseof.html, _default/baseof.html.
+func (tr *templateResolver) findBase(kind pageKind, section string) string {
+	candidates := []string{}
+	if section != "" {
+		candidates = append(candidates,
+			fp.Join(tr.themeDir, section, string(kind)+"-baseof.html"),
+			fp.Join(tr.themeDir, section, "baseof.html"),
+		)
+	}
+	candidates = append(candidates,
+		fp.Join(tr.themeDir, "_default", string(kind)+"-baseof.html"),
+		fp.Join(tr.themeDir, "_default", "baseof.html"),
+	)
+
+	return firstExisting(candidates)
+}
+
+// findPartials fetches the list of partial templates used in the theme.
+// A partial is any top-level HTML file prefixed with an underscore
+// character, e.g. _footer.html, _header.html, etc.
+func (tr *templateResolver) findPartials() ([]string, error) {
+	items, err := ioutil.ReadDir(tr.themeDir)
+	if err != nil {
+		return []string{}, err
+	}
+
+	partials := []string{}
+	for _, item := range items {
+		if item.IsDir() {
+			continue
+		}
+
+		if strings.HasSuffix(item.Name(), ".html") && strings.HasPrefix(item.Name(), "_") {
+			partials = append(partials, fp.Join(tr.themeDir, item.Name()))
+		}
+	}
+
+	return partials, nil
+}
+
+// firstExisting returns the first candidate path that exists on disk, or
+// an empty string if none of them do.
+func firstExisting(candidates []string) string {
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}